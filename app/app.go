@@ -6,89 +6,168 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
-	"time"
 
+	"profile-views-counter/app/routes/stats"
 	"profile-views-counter/conf"
+	"profile-views-counter/metrics"
+	"profile-views-counter/ratelimit"
 
 	"github.com/gorilla/mux"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 )
 
 type App struct {
 	Config                *conf.Config
 	Logger                *zap.Logger
+	LogLevel              zap.AtomicLevel
+	ConfigWatcher         *conf.Watcher
 	DbRegistry            *DBRegistry
 	ServiceRegistry       *ServiceRegistry
 	HTTPServer            *http.Server
+	AdminHTTPServer       *http.Server
+	BadgeTarget           *stats.Target
 	ShieldsIOReverseProxy *httputil.ReverseProxy
 }
 
-func New(cfg *conf.Config, logger *zap.Logger) (*App, error) {
+func New(cfg *conf.Config, logger *zap.Logger, logLevel zap.AtomicLevel) (*App, error) {
 	logger.Info("connecting to database")
 	dbPool, err := pgxpool.Connect(context.Background(), cfg.DatabaseURL)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to connect to postgres database")
 	}
 
-	badgeURL, err := url.Parse("https://img.shields.io/static/v1")
-	if err != nil {
-		return nil, errors.Wrap(err, "incorrect shields.io url")
+	if err := Migrate(context.Background(), dbPool); err != nil {
+		return nil, err
 	}
+	prometheus.MustRegister(metrics.NewPoolCollector(dbPool))
 
-	badgeReverseProxy := httputil.NewSingleHostReverseProxy(badgeURL)
-	badgeReverseProxy.Director = func(req *http.Request) {
-		req.Header.Add("X-Forwarded-Host", req.Host)
-		req.Header.Add("X-Origin-Host", badgeURL.Host)
-		req.Header.Add("Cache-Control", "no-cache")
-		req.URL.Scheme = badgeURL.Scheme
-		req.URL.Host = badgeURL.Host
-		req.Host = badgeURL.Host
-		req.URL.Path = badgeURL.Path
+	configWatcher, err := conf.NewWatcher(cfg, cfg.ConfigFile)
+	if err != nil {
+		return nil, err
 	}
-	badgeReverseProxy.Transport = &http.Transport{
-		MaxIdleConnsPerHost: 20,
-		MaxConnsPerHost:     20,
-		IdleConnTimeout:     12 * time.Hour,
+
+	badgeURL, err := url.Parse(cfg.ShieldsIOURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "incorrect shields.io url")
 	}
+	badgeTarget := stats.NewTarget(badgeURL)
+	badgeReverseProxy := stats.NewReverseProxy(badgeTarget)
 
 	dbRegistry := NewDBRegistry(dbPool)
-	serviceRegistry := NewServiceRegistry(dbRegistry, badgeReverseProxy)
+	serviceRegistry := NewServiceRegistry(dbRegistry, badgeReverseProxy, cfg.ServiceUserMap, cfg.BadgeBackend)
+
+	botMatcher, err := ratelimit.NewBotMatcher(cfg.BotUserAgents)
+	if err != nil {
+		return nil, err
+	}
+	rateLimiter := ratelimit.NewMiddleware(ratelimit.NewMemoryStore(cfg.DedupeWindow), botMatcher, cfg.RateLimitRPS, cfg.RateLimitBurst)
+
 	router := mux.NewRouter()
-	RegisterRoutes(router, serviceRegistry)
+	RegisterRoutes(router, serviceRegistry, rateLimiter)
+
+	adminRouter := mux.NewRouter()
+	RegisterAdminRoutes(adminRouter)
 
 	app := &App{
 		Config:          cfg,
 		Logger:          logger,
+		LogLevel:        logLevel,
+		ConfigWatcher:   configWatcher,
 		DbRegistry:      dbRegistry,
 		ServiceRegistry: serviceRegistry,
 		HTTPServer: &http.Server{
 			Addr:    fmt.Sprintf(":%d", cfg.Port),
 			Handler: router,
 		},
+		AdminHTTPServer: &http.Server{
+			Addr:    fmt.Sprintf(":%d", cfg.AdminPort),
+			Handler: adminRouter,
+		},
+		BadgeTarget:           badgeTarget,
 		ShieldsIOReverseProxy: badgeReverseProxy,
 	}
 
+	// NewWatcher already merged cfg.ConfigFile into configWatcher.Current(),
+	// but that merge was published before anyone subscribed, so it must be
+	// applied explicitly here rather than relying on the update channel.
+	updates := configWatcher.Subscribe()
+	app.applyConfig(configWatcher.Current())
+	go app.watchConfig(updates)
+
 	return app, nil
 }
 
+// watchConfig applies every config published by ConfigWatcher to the
+// parts of the app that support hot-reload, without restarting the HTTP
+// server.
+func (a *App) watchConfig(updates <-chan *conf.Config) {
+	for cfg := range updates {
+		a.applyConfig(cfg)
+	}
+}
+
+// applyConfig pushes cfg's hot-reloadable fields into the running app.
+func (a *App) applyConfig(cfg *conf.Config) {
+	a.ServiceRegistry.StatsService.SetServiceUserMap(cfg.ServiceUserMap)
+
+	if badgeURL, err := url.Parse(cfg.ShieldsIOURL); err == nil {
+		a.BadgeTarget.Set(badgeURL)
+	} else {
+		a.Logger.Warn("ignoring invalid shields.io url from reloaded config", zap.Error(err))
+	}
+
+	if err := a.LogLevel.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		a.Logger.Warn("ignoring invalid log level from reloaded config", zap.Error(err))
+	}
+
+	a.Logger.Info("applied configuration")
+}
+
 func (a *App) Start() chan error {
+	rtn := make(chan error, 2)
+
 	a.Logger.Info("starting server", zap.String("port", a.HTTPServer.Addr))
-	rtn := make(chan error)
 	go func() {
 		rtn <- a.HTTPServer.ListenAndServe()
 	}()
+
+	a.Logger.Info("starting admin server", zap.String("port", a.AdminHTTPServer.Addr))
+	go func() {
+		rtn <- a.AdminHTTPServer.ListenAndServe()
+	}()
+
 	return rtn
 }
 
-func (a *App) ShutDown() {
+func (a *App) ShutDown(ctx context.Context) {
 	a.Logger.Info("shutting down application")
+
+	a.Logger.Info("draining http server")
+	if err := a.HTTPServer.Shutdown(ctx); err != nil {
+		a.Logger.Error("failed to gracefully shut down http server", zap.Error(err))
+	}
+
+	a.Logger.Info("draining admin server")
+	if err := a.AdminHTTPServer.Shutdown(ctx); err != nil {
+		a.Logger.Error("failed to gracefully shut down admin server", zap.Error(err))
+	}
+
 	a.Logger.Info("closing database connection")
 	a.DbRegistry.Pool.Close()
 }
 
-func RegisterRoutes(router *mux.Router, sr *ServiceRegistry) {
-	router.HandleFunc("/stats/{service}/{user}/count.svg", sr.StatsService.Handler).
+func RegisterRoutes(router *mux.Router, sr *ServiceRegistry, rl *ratelimit.Middleware) {
+	router.HandleFunc("/stats/{service}/{user}/count.svg", metrics.InstrumentHandler(rl.Wrap(sr.StatsService.Handler))).
 		Methods(http.MethodGet).Name("ProfileCountBadge")
 }
+
+// RegisterAdminRoutes wires up endpoints meant for operators only. It's
+// served on its own port so it doesn't share a listener with public
+// badge traffic.
+func RegisterAdminRoutes(router *mux.Router) {
+	router.Handle("/metrics", promhttp.Handler()).Methods(http.MethodGet).Name("Metrics")
+}
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// schema creates the tables backing the view counters. Statements are
+// idempotent so this can safely run on every boot.
+const schema = `
+CREATE TABLE IF NOT EXISTS services (
+	id   serial PRIMARY KEY,
+	name text NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS counts (
+	service_id int    NOT NULL REFERENCES services (id),
+	"user"     text   NOT NULL,
+	count      bigint NOT NULL DEFAULT 0,
+	PRIMARY KEY (service_id, "user")
+);
+`
+
+// Migrate applies the database schema required by the application.
+func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	if _, err := pool.Exec(ctx, schema); err != nil {
+		return errors.Wrap(err, "failed to apply database schema")
+	}
+	return nil
+}
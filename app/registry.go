@@ -1,6 +1,8 @@
 package app
 
 import (
+	"net/http/httputil"
+
 	"profile-views-counter/app/routes/stats"
 
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -18,6 +20,8 @@ type ServiceRegistry struct {
 	StatsService *stats.Service
 }
 
-func NewServiceRegistry(dbr *DBRegistry) *ServiceRegistry {
-	return &ServiceRegistry{StatsService: stats.NewService(dbr.Pool)}
+func NewServiceRegistry(dbr *DBRegistry, badgeReverseProxy *httputil.ReverseProxy, serviceUserMap map[string]string, badgeBackend string) *ServiceRegistry {
+	return &ServiceRegistry{
+		StatsService: stats.NewService(stats.NewPgCountStore(dbr.Pool), badgeReverseProxy, serviceUserMap, badgeBackend),
+	}
 }
@@ -0,0 +1,94 @@
+package stats
+
+import (
+	"context"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"profile-views-counter/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type contextKey int
+
+const (
+	labelContextKey contextKey = iota
+	countContextKey
+)
+
+// withBadgeValues attaches the label/count pair the Director needs to
+// build the shields.io query string for this request.
+func withBadgeValues(r *http.Request, label string, count int64) *http.Request {
+	ctx := context.WithValue(r.Context(), labelContextKey, label)
+	ctx = context.WithValue(ctx, countContextKey, count)
+	return r.WithContext(ctx)
+}
+
+// Target holds the shields.io upstream URL the reverse proxy rewrites
+// every request to. It can be swapped at runtime, e.g. by a config
+// watcher, without restarting the HTTP server.
+type Target struct {
+	url atomic.Pointer[url.URL]
+}
+
+// NewTarget builds a Target pointed at u.
+func NewTarget(u *url.URL) *Target {
+	t := &Target{}
+	t.Set(u)
+	return t
+}
+
+// Set swaps the upstream URL.
+func (t *Target) Set(u *url.URL) {
+	t.url.Store(u)
+}
+
+// Get returns the current upstream URL.
+func (t *Target) Get() *url.URL {
+	return t.url.Load()
+}
+
+// NewReverseProxy builds the shields.io badge reverse proxy. The Director
+// rewrites every request to target's current badge URL, injecting the
+// label and count stashed on the request context by Service.Handler.
+func NewReverseProxy(target *Target) *httputil.ReverseProxy {
+	proxy := &httputil.ReverseProxy{}
+	proxy.Director = func(req *http.Request) {
+		badgeURL := target.Get()
+
+		req.Header.Add("X-Forwarded-Host", req.Host)
+		req.Header.Add("X-Origin-Host", badgeURL.Host)
+		req.Header.Add("Cache-Control", "no-cache")
+		req.URL.Scheme = badgeURL.Scheme
+		req.URL.Host = badgeURL.Host
+		req.Host = badgeURL.Host
+		req.URL.Path = badgeURL.Path
+
+		color := req.URL.Query().Get("color")
+		if color == "" {
+			color = "blue"
+		}
+
+		label, _ := req.Context().Value(labelContextKey).(string)
+		count, _ := req.Context().Value(countContextKey).(int64)
+
+		query := url.Values{
+			"label":   {label},
+			"message": {strconv.FormatInt(count, 10)},
+			"color":   {color},
+		}
+		req.URL.RawQuery = query.Encode()
+	}
+	transport := &http.Transport{
+		MaxIdleConnsPerHost: 20,
+		MaxConnsPerHost:     20,
+		IdleConnTimeout:     12 * time.Hour,
+	}
+	proxy.Transport = promhttp.InstrumentRoundTripperDuration(metrics.ShieldsUpstreamDuration, transport)
+	return proxy
+}
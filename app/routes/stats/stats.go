@@ -3,19 +3,77 @@ package stats
 import (
 	"net/http"
 	"net/http/httputil"
+	"strconv"
+	"sync/atomic"
 
-	"github.com/jackc/pgx/v4/pgxpool"
+	"profile-views-counter/badge"
+	"profile-views-counter/conf"
+	"profile-views-counter/ratelimit"
+
+	"github.com/gorilla/mux"
 )
 
 type Service struct {
-	db                *pgxpool.Pool
+	store             CountStore
 	badgeReverseProxy *httputil.ReverseProxy
+	serviceUserMap    atomic.Pointer[map[string]string]
+	badgeBackend      string
+}
+
+func NewService(store CountStore, reverseProxy *httputil.ReverseProxy, serviceUserMap map[string]string, badgeBackend string) *Service {
+	s := &Service{store: store, badgeReverseProxy: reverseProxy, badgeBackend: badgeBackend}
+	s.SetServiceUserMap(serviceUserMap)
+	return s
 }
 
-func NewService(db *pgxpool.Pool, reverseProxy *httputil.ReverseProxy) *Service {
-	return &Service{db, reverseProxy}
+// SetServiceUserMap swaps the whitelist/alias map at runtime, e.g. when
+// the backing config file changes.
+func (s *Service) SetServiceUserMap(serviceUserMap map[string]string) {
+	s.serviceUserMap.Store(&serviceUserMap)
 }
 
 func (s *Service) Handler(w http.ResponseWriter, r *http.Request) {
-	s.badgeReverseProxy.ServeHTTP(w, r)
+	vars := mux.Vars(r)
+	service, user := vars["service"], vars["user"]
+
+	label, ok := (*s.serviceUserMap.Load())[service]
+	if !ok {
+		http.Error(w, "unknown service", http.StatusNotFound)
+		return
+	}
+
+	incrementAndGet := s.store.IncrementAndGet
+	if ratelimit.ShouldSkipIncrement(r) {
+		incrementAndGet = s.store.Get
+	}
+
+	count, err := incrementAndGet(r.Context(), service, user)
+	if err != nil {
+		http.Error(w, "failed to record view", http.StatusInternalServerError)
+		return
+	}
+
+	if s.badgeBackend == conf.BadgeBackendShields {
+		s.badgeReverseProxy.ServeHTTP(w, withBadgeValues(r, label, count))
+		return
+	}
+
+	s.renderLocalBadge(w, r, label, count)
+}
+
+func (s *Service) renderLocalBadge(w http.ResponseWriter, r *http.Request, label string, count int64) {
+	color := r.URL.Query().Get("color")
+	if color == "" {
+		color = "blue"
+	}
+
+	svg, err := badge.Render(label, strconv.FormatInt(count, 10), color, badge.Style(r.URL.Query().Get("style")))
+	if err != nil {
+		http.Error(w, "failed to render badge", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "max-age=0, no-cache")
+	w.Write([]byte(svg))
 }
@@ -0,0 +1,94 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"profile-views-counter/conf"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCountStore struct {
+	count int64
+	err   error
+}
+
+func (f *fakeCountStore) IncrementAndGet(ctx context.Context, service, user string) (int64, error) {
+	return f.count, f.err
+}
+
+func (f *fakeCountStore) Get(ctx context.Context, service, user string) (int64, error) {
+	return f.count, f.err
+}
+
+func newTestRouter(svc *Service) *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/stats/{service}/{user}/count.svg", svc.Handler)
+	return router
+}
+
+func TestHandler_UnknownService(t *testing.T) {
+	svc := NewService(&fakeCountStore{count: 1}, nil, map[string]string{"github": "Profile views"}, conf.BadgeBackendLocal)
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/stats/unknown/vivek-26/count.svg", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandler_StoreError(t *testing.T) {
+	svc := NewService(&fakeCountStore{err: errors.New("connection lost")}, nil, map[string]string{"github": "Profile views"}, conf.BadgeBackendLocal)
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/stats/github/vivek-26/count.svg", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestHandler_LocalBackendRendersSVG(t *testing.T) {
+	svc := NewService(&fakeCountStore{count: 42}, nil, map[string]string{"github": "Profile views"}, conf.BadgeBackendLocal)
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/stats/github/vivek-26/count.svg", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "image/svg+xml", rec.Header().Get("Content-Type"))
+	require.Contains(t, rec.Body.String(), "Profile views")
+	require.Contains(t, rec.Body.String(), "42")
+}
+
+func TestHandler_ShieldsBackendProxiesUpstream(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Profile views", r.URL.Query().Get("label"))
+		require.Equal(t, "42", r.URL.Query().Get("message"))
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Write([]byte("<svg>from upstream</svg>"))
+	}))
+	defer upstream.Close()
+
+	upstreamURL, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+
+	proxy := NewReverseProxy(NewTarget(upstreamURL))
+	svc := NewService(&fakeCountStore{count: 42}, proxy, map[string]string{"github": "Profile views"}, conf.BadgeBackendShields)
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/stats/github/vivek-26/count.svg", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "from upstream")
+}
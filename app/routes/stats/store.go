@@ -0,0 +1,89 @@
+package stats
+
+import (
+	"context"
+
+	"profile-views-counter/metrics"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+)
+
+const (
+	opIncrementAndGet = "increment_and_get"
+	opGet             = "get"
+)
+
+// CountStore records a view for a service/user pair and returns the
+// updated total.
+type CountStore interface {
+	IncrementAndGet(ctx context.Context, service, user string) (int64, error)
+	// Get returns the current count for a service/user pair without
+	// incrementing it, e.g. for duplicate or bot hits. It returns 0 if
+	// the pair has never been seen.
+	Get(ctx context.Context, service, user string) (int64, error)
+}
+
+// querier is the slice of pgxpool.Pool that pgCountStore relies on, kept
+// narrow so it can be exercised against pgxmock in tests.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// pgCountStore is the pgxpool-backed implementation of CountStore.
+type pgCountStore struct {
+	db querier
+}
+
+// NewPgCountStore builds a CountStore backed by the given pool.
+func NewPgCountStore(db *pgxpool.Pool) CountStore {
+	return &pgCountStore{db: db}
+}
+
+// incrementAndGetQuery upserts the service row and atomically bumps the
+// matching count, returning the new total.
+const incrementAndGetQuery = `
+WITH svc AS (
+	INSERT INTO services (name) VALUES ($1)
+	ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+	RETURNING id
+)
+INSERT INTO counts (service_id, "user", count)
+SELECT svc.id, $2, 1 FROM svc
+ON CONFLICT (service_id, "user") DO UPDATE SET count = counts.count + 1
+RETURNING count;
+`
+
+func (s *pgCountStore) IncrementAndGet(ctx context.Context, service, user string) (int64, error) {
+	var count int64
+	err := s.db.QueryRow(ctx, incrementAndGetQuery, service, user).Scan(&count)
+	if err != nil {
+		metrics.DBQueriesTotal.WithLabelValues(opIncrementAndGet, "error").Inc()
+		return 0, errors.Wrap(err, "failed to increment count")
+	}
+	metrics.DBQueriesTotal.WithLabelValues(opIncrementAndGet, "success").Inc()
+	return count, nil
+}
+
+// getCountQuery looks up the current count without modifying it.
+const getCountQuery = `
+SELECT c.count
+FROM counts c
+JOIN services s ON s.id = c.service_id
+WHERE s.name = $1 AND c."user" = $2;
+`
+
+func (s *pgCountStore) Get(ctx context.Context, service, user string) (int64, error) {
+	var count int64
+	err := s.db.QueryRow(ctx, getCountQuery, service, user).Scan(&count)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		metrics.DBQueriesTotal.WithLabelValues(opGet, "error").Inc()
+		return 0, errors.Wrap(err, "failed to get count")
+	}
+	metrics.DBQueriesTotal.WithLabelValues(opGet, "success").Inc()
+	return count, nil
+}
@@ -0,0 +1,44 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPgCountStore_IncrementAndGet(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(incrementAndGetQuery)).
+		WithArgs("github", "vivek-26").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(int64(7)))
+
+	store := &pgCountStore{db: mock}
+
+	count, err := store.IncrementAndGet(context.Background(), "github", "vivek-26")
+	require.NoError(t, err)
+	require.Equal(t, int64(7), count)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPgCountStore_IncrementAndGet_QueryError(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(incrementAndGetQuery)).
+		WithArgs("github", "vivek-26").
+		WillReturnError(errors.New("connection lost"))
+
+	store := &pgCountStore{db: mock}
+
+	_, err = store.IncrementAndGet(context.Background(), "github", "vivek-26")
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,65 @@
+// Package badge renders shields.io-compatible SVG badges in-process, so
+// the app doesn't have to make an outbound call per request.
+package badge
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Style selects which shields.io badge layout to render.
+type Style string
+
+const (
+	StyleFlat        Style = "flat"
+	StyleFlatSquare  Style = "flat-square"
+	StyleForTheBadge Style = "for-the-badge"
+)
+
+const padding = 10
+
+// Render draws a shields.io-compatible "static/v1" badge: a label on the
+// left, a message on the right, colored by color. Unknown styles fall
+// back to StyleFlat.
+func Render(label, message, color string, style Style) (string, error) {
+	tmpl, ok := templates[style]
+	if !ok {
+		tmpl = templates[StyleFlat]
+	}
+
+	if style == StyleForTheBadge {
+		label = strings.ToUpper(label)
+		message = strings.ToUpper(message)
+	}
+
+	labelWidth := int(textWidth(label) + 2*padding)
+	messageWidth := int(textWidth(message) + 2*padding)
+
+	data := struct {
+		Label        string
+		Message      string
+		Color        string
+		LabelWidth   int
+		MessageWidth int
+		TotalWidth   int
+		LabelX       int
+		MessageX     int
+	}{
+		Label:        label,
+		Message:      message,
+		Color:        resolveColor(color),
+		LabelWidth:   labelWidth,
+		MessageWidth: messageWidth,
+		TotalWidth:   labelWidth + messageWidth,
+		LabelX:       labelWidth / 2,
+		MessageX:     labelWidth + messageWidth/2,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrap(err, "failed to render badge")
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,42 @@
+package badge
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_Flat(t *testing.T) {
+	svg, err := Render("profile views", "42", "brightgreen", StyleFlat)
+	require.NoError(t, err)
+	require.Contains(t, svg, "<svg")
+	require.Contains(t, svg, "profile views")
+	require.Contains(t, svg, "42")
+	require.Contains(t, svg, "#4c1")
+}
+
+func TestRender_ForTheBadgeUppercasesText(t *testing.T) {
+	svg, err := Render("profile views", "42", "blue", StyleForTheBadge)
+	require.NoError(t, err)
+	require.Contains(t, svg, "PROFILE VIEWS")
+}
+
+func TestRender_UnknownStyleFallsBackToFlat(t *testing.T) {
+	svg, err := Render("profile views", "42", "blue", Style("made-up"))
+	require.NoError(t, err)
+	require.True(t, strings.Contains(svg, "url(#s)"), "expected flat-style gradient fill")
+}
+
+func TestRender_EscapesInjectedColor(t *testing.T) {
+	svg, err := Render("views", "42", `"/><script>alert(1)</script>x`, StyleFlat)
+	require.NoError(t, err)
+	require.NotContains(t, svg, "<script>")
+	require.Contains(t, svg, "&lt;script&gt;")
+}
+
+func TestResolveColor(t *testing.T) {
+	require.Equal(t, "#4c1", resolveColor("brightgreen"))
+	require.Equal(t, "#abcdef", resolveColor("#abcdef"))
+	require.Equal(t, "#ff0000", resolveColor("ff0000"))
+}
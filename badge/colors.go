@@ -0,0 +1,30 @@
+package badge
+
+import "strings"
+
+// namedColors maps shields.io's named palette to hex values.
+var namedColors = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"yellow":      "#dfb317",
+	"yellowgreen": "#a4a61d",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+	"blue":        "#007ec6",
+	"grey":        "#555",
+	"gray":        "#555",
+	"lightgrey":   "#9f9f9f",
+	"lightgray":   "#9f9f9f",
+}
+
+// resolveColor returns the hex value for a named color, or the input
+// unchanged if it already looks like a CSS color (e.g. "#abcdef").
+func resolveColor(color string) string {
+	if hex, ok := namedColors[strings.ToLower(color)]; ok {
+		return hex
+	}
+	if strings.HasPrefix(color, "#") {
+		return color
+	}
+	return "#" + color
+}
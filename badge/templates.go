@@ -0,0 +1,54 @@
+package badge
+
+import "html/template"
+
+const flatTemplate = `<svg xmlns="http://www.w3.org/2000/svg" width="{{.TotalWidth}}" height="20" role="img" aria-label="{{.Label}}: {{.Message}}">
+  <linearGradient id="s" x2="0" y2="100%">
+    <stop offset="0" stop-color="#bbb" stop-opacity=".1"/>
+    <stop offset="1" stop-opacity=".1"/>
+  </linearGradient>
+  <clipPath id="r">
+    <rect width="{{.TotalWidth}}" height="20" rx="3" fill="#fff"/>
+  </clipPath>
+  <g clip-path="url(#r)">
+    <rect width="{{.LabelWidth}}" height="20" fill="#555"/>
+    <rect x="{{.LabelWidth}}" width="{{.MessageWidth}}" height="20" fill="{{.Color}}"/>
+    <rect width="{{.TotalWidth}}" height="20" fill="url(#s)"/>
+  </g>
+  <g fill="#fff" text-anchor="middle" font-family="DejaVu Sans,Verdana,Geneva,sans-serif" font-size="11">
+    <text x="{{.LabelX}}" y="14">{{.Label}}</text>
+    <text x="{{.MessageX}}" y="14">{{.Message}}</text>
+  </g>
+</svg>
+`
+
+const flatSquareTemplate = `<svg xmlns="http://www.w3.org/2000/svg" width="{{.TotalWidth}}" height="20" role="img" aria-label="{{.Label}}: {{.Message}}">
+  <g>
+    <rect width="{{.LabelWidth}}" height="20" fill="#555"/>
+    <rect x="{{.LabelWidth}}" width="{{.MessageWidth}}" height="20" fill="{{.Color}}"/>
+  </g>
+  <g fill="#fff" text-anchor="middle" font-family="DejaVu Sans,Verdana,Geneva,sans-serif" font-size="11">
+    <text x="{{.LabelX}}" y="14">{{.Label}}</text>
+    <text x="{{.MessageX}}" y="14">{{.Message}}</text>
+  </g>
+</svg>
+`
+
+const forTheBadgeTemplate = `<svg xmlns="http://www.w3.org/2000/svg" width="{{.TotalWidth}}" height="28" role="img" aria-label="{{.Label}}: {{.Message}}">
+  <g>
+    <rect width="{{.LabelWidth}}" height="28" fill="#555"/>
+    <rect x="{{.LabelWidth}}" width="{{.MessageWidth}}" height="28" fill="{{.Color}}"/>
+  </g>
+  <g fill="#fff" text-anchor="middle" font-family="DejaVu Sans,Verdana,Geneva,sans-serif" font-size="11" font-weight="bold">
+    <text x="{{.LabelX}}" y="18">{{.Label}}</text>
+    <text x="{{.MessageX}}" y="18">{{.Message}}</text>
+  </g>
+</svg>
+`
+
+// templates holds the parsed SVG template for each supported Style.
+var templates = map[Style]*template.Template{
+	StyleFlat:        template.Must(template.New("flat").Parse(flatTemplate)),
+	StyleFlatSquare:  template.Must(template.New("flat-square").Parse(flatSquareTemplate)),
+	StyleForTheBadge: template.Must(template.New("for-the-badge").Parse(forTheBadgeTemplate)),
+}
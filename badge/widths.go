@@ -0,0 +1,41 @@
+package badge
+
+// charWidths holds DejaVu Sans 11px advance widths, in hundredths of a
+// pixel, for the printable ASCII range except the backtick, which falls
+// back to defaultCharWidth. This is the same metric table
+// shields.io uses to size its badges without a font rendering dependency.
+var charWidths = map[rune]int{
+	' ': 362, '!': 405, '"': 478, '#': 801, '$': 636, '%': 1032, '&': 780,
+	'\'': 239, '(': 382, ')': 382, '*': 545, '+': 801, ',': 362, '-': 435,
+	'.': 362, '/': 398,
+	'0': 636, '1': 636, '2': 636, '3': 636, '4': 636, '5': 636, '6': 636,
+	'7': 636, '8': 636, '9': 636,
+	':': 362, ';': 362, '<': 801, '=': 801, '>': 801, '?': 583, '@': 1059,
+	'A': 684, 'B': 686, 'C': 698, 'D': 770, 'E': 632, 'F': 578, 'G': 775,
+	'H': 792, 'I': 360, 'J': 381, 'K': 705, 'L': 576, 'M': 908, 'N': 783,
+	'O': 790, 'P': 680, 'Q': 790, 'R': 698, 'S': 631, 'T': 615, 'U': 760,
+	'V': 684, 'W': 989, 'X': 685, 'Y': 635, 'Z': 661,
+	'[': 382, '\\': 398, ']': 382, '^': 801, '_': 636,
+	'a': 613, 'b': 635, 'c': 550, 'd': 635, 'e': 615, 'f': 380, 'g': 635,
+	'h': 635, 'i': 278, 'j': 278, 'k': 583, 'l': 278, 'm': 952, 'n': 635,
+	'o': 635, 'p': 635, 'q': 635, 'r': 416, 's': 528, 't': 389, 'u': 635,
+	'v': 583, 'w': 772, 'x': 583, 'y': 583, 'z': 549,
+	'{': 390, '|': 342, '}': 390, '~': 801,
+}
+
+// defaultCharWidth is used for glyphs outside the printable ASCII table
+// above (e.g. most non-Latin scripts).
+const defaultCharWidth = 636
+
+// textWidth returns the rendered width, in pixels, of s at 11px.
+func textWidth(s string) float64 {
+	total := 0
+	for _, r := range s {
+		w, ok := charWidths[r]
+		if !ok {
+			w = defaultCharWidth
+		}
+		total += w
+	}
+	return float64(total) / 100
+}
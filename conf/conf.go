@@ -1,14 +1,48 @@
 package conf
 
 import (
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
+// Supported values for Config.BadgeBackend.
+const (
+	BadgeBackendLocal   = "local"
+	BadgeBackendShields = "shields"
+)
+
 // Config has all app configurations
 type Config struct {
-	Port           int               `default:"9000"`
-	DatabaseURL    string            `split_words:"true" required:"true"`
-	ServiceUserMap map[string]string `split_words:"true" required:"true"`
+	Port            int               `default:"9000"`
+	DatabaseURL     string            `split_words:"true" required:"true"`
+	ServiceUserMap  map[string]string `split_words:"true" required:"true"`
+	ShutdownTimeout time.Duration     `split_words:"true" default:"30s"`
+	// BadgeBackend selects how the count.svg badge is rendered: "local"
+	// renders it in-process, "shields" proxies to img.shields.io.
+	BadgeBackend string `split_words:"true" default:"local"`
+	// AdminPort serves operator-only endpoints (currently /metrics), kept
+	// off the public badge-serving port.
+	AdminPort int `split_words:"true" default:"9001"`
+	// ShieldsIOURL is the shields.io endpoint used when BadgeBackend is
+	// "shields".
+	ShieldsIOURL string `split_words:"true" default:"https://img.shields.io/static/v1"`
+	// LogLevel is the zap level name (debug, info, warn, error).
+	LogLevel string `split_words:"true" default:"info"`
+	// ConfigFile optionally points at a YAML file that hot-reloads
+	// ServiceUserMap, ShieldsIOURL and LogLevel without a restart. Env
+	// vars always take precedence over values read from this file.
+	ConfigFile string `split_words:"true"`
+	// BotUserAgents are regexes matched against the User-Agent header;
+	// a match is served a badge without incrementing its count.
+	BotUserAgents []string `split_words:"true" default:"github-camo,Slackbot,Twitterbot,Discordbot,facebookexternalhit"`
+	// DedupeWindow is how long a (remote IP /24, User-Agent, service,
+	// user) tuple suppresses further increments for.
+	DedupeWindow time.Duration `split_words:"true" default:"12h"`
+	// RateLimitRPS and RateLimitBurst configure the per-source-IP token
+	// bucket guarding against pathological request bursts.
+	RateLimitRPS   float64 `split_words:"true" default:"5"`
+	RateLimitBurst int     `split_words:"true" default:"10"`
 }
 
 // Load reads all env vars needed by application
@@ -0,0 +1,106 @@
+package conf
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// fileConfig is the subset of Config that can be hot-reloaded from the
+// optional YAML file pointed at by Config.ConfigFile.
+type fileConfig struct {
+	ServiceUserMap map[string]string `mapstructure:"service_user_map"`
+	ShieldsIOURL   string            `mapstructure:"shields_io_url"`
+	LogLevel       string            `mapstructure:"log_level"`
+}
+
+// Watcher holds the current Config and publishes a new one, derived from
+// the YAML file, whenever that file changes. Env vars are the
+// highest-precedence override: a field already set via its env var is
+// never clobbered by the file.
+type Watcher struct {
+	current atomic.Pointer[Config]
+
+	mu   sync.Mutex
+	subs []chan *Config
+}
+
+// NewWatcher wraps initial and, if path is non-empty, starts watching it
+// for changes.
+func NewWatcher(initial *Config, path string) (*Watcher, error) {
+	w := &Watcher{}
+	w.current.Store(initial)
+
+	if path == "" {
+		return w, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, errors.Wrap(err, "failed to read config file")
+	}
+	w.applyFile(v)
+
+	v.OnConfigChange(func(fsnotify.Event) { w.applyFile(v) })
+	v.WatchConfig()
+
+	return w, nil
+}
+
+// Current returns the most recently published Config.
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Subscribe returns a channel that receives the Configs published after
+// a file change. The channel is buffered by one; a slow subscriber only
+// ever sees the latest value.
+func (w *Watcher) Subscribe() <-chan *Config {
+	ch := make(chan *Config, 1)
+	w.mu.Lock()
+	w.subs = append(w.subs, ch)
+	w.mu.Unlock()
+	return ch
+}
+
+func (w *Watcher) applyFile(v *viper.Viper) {
+	var fc fileConfig
+	if err := v.Unmarshal(&fc); err != nil {
+		return
+	}
+
+	next := *w.current.Load()
+	if _, fromEnv := os.LookupEnv("SERVICE_USER_MAP"); !fromEnv && len(fc.ServiceUserMap) > 0 {
+		next.ServiceUserMap = fc.ServiceUserMap
+	}
+	if _, fromEnv := os.LookupEnv("SHIELDS_IO_URL"); !fromEnv && fc.ShieldsIOURL != "" {
+		next.ShieldsIOURL = fc.ShieldsIOURL
+	}
+	if _, fromEnv := os.LookupEnv("LOG_LEVEL"); !fromEnv && fc.LogLevel != "" {
+		next.LogLevel = fc.LogLevel
+	}
+
+	w.publish(&next)
+}
+
+func (w *Watcher) publish(next *Config) {
+	w.current.Store(next)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, ch := range w.subs {
+		// Drop a value the subscriber hasn't read yet so the buffer holds
+		// the latest Config. publish is the only sender and holds w.mu, so
+		// the send below never blocks.
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- next
+	}
+}
@@ -0,0 +1,62 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	return path
+}
+
+func TestWatcher_NoPath(t *testing.T) {
+	initial := &Config{LogLevel: "info"}
+	w, err := NewWatcher(initial, "")
+	require.NoError(t, err)
+	require.Equal(t, initial, w.Current())
+}
+
+func TestWatcher_AppliesFileOnLoad(t *testing.T) {
+	path := writeConfigFile(t, "log_level: debug\n")
+
+	initial := &Config{LogLevel: "info"}
+	w, err := NewWatcher(initial, path)
+	require.NoError(t, err)
+	require.Equal(t, "debug", w.Current().LogLevel)
+}
+
+func TestWatcher_EnvVarTakesPrecedenceOverFile(t *testing.T) {
+	path := writeConfigFile(t, "log_level: debug\n")
+
+	t.Setenv("LOG_LEVEL", "warn")
+
+	initial := &Config{LogLevel: "warn"}
+	w, err := NewWatcher(initial, path)
+	require.NoError(t, err)
+	require.Equal(t, "warn", w.Current().LogLevel)
+}
+
+func TestWatcher_SubscribePublishesUpdates(t *testing.T) {
+	path := writeConfigFile(t, "log_level: debug\n")
+
+	initial := &Config{LogLevel: "info"}
+	w, err := NewWatcher(initial, path)
+	require.NoError(t, err)
+
+	ch := w.Subscribe()
+	w.publish(&Config{LogLevel: "error"})
+
+	select {
+	case next := <-ch:
+		require.Equal(t, "error", next.LogLevel)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for published config")
+	}
+}
@@ -0,0 +1,44 @@
+// Package metrics holds the application's Prometheus instrumentation,
+// exposed on a separate admin port so it isn't reachable alongside
+// public badge traffic.
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	// RequestsTotal counts count.svg requests by service and response
+	// status. The user path segment is deliberately not a label: it's
+	// unwhitelisted attacker-controlled input on a public endpoint, and
+	// client_golang never expires label combinations, so including it
+	// would let anyone grow this series unboundedly.
+	RequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pvc_requests_total",
+		Help: "Total count.svg requests, labeled by service and response status.",
+	}, []string{"service", "status"})
+
+	// RequestDuration tracks end-to-end latency of count.svg requests.
+	RequestDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "pvc_request_duration_seconds",
+		Help:    "Latency of count.svg requests.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// ShieldsUpstreamDuration tracks the shields.io reverse-proxy
+	// roundtrip. It's shaped for promhttp.InstrumentRoundTripperDuration,
+	// whose only allowed non-curried labels are "code" and "method".
+	ShieldsUpstreamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pvc_shields_upstream_duration_seconds",
+		Help:    "Latency of the shields.io reverse-proxy roundtrip.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+
+	// DBQueriesTotal counts database queries by operation and result.
+	DBQueriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pvc_db_queries_total",
+		Help: "Total database queries, labeled by operation and result.",
+	}, []string{"op", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(RequestsTotal, RequestDuration, ShieldsUpstreamDuration, DBQueriesTotal)
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// statusRecorder captures the status code written by the wrapped handler
+// so it can be reported after the fact.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// InstrumentHandler records RequestsTotal and RequestDuration for every
+// call to next. It must run after route matching so mux.Vars(r) resolves.
+func InstrumentHandler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next(rec, r)
+
+		vars := mux.Vars(r)
+		RequestsTotal.WithLabelValues(vars["service"], strconv.Itoa(rec.status)).Inc()
+		RequestDuration.Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentHandler_RecordsStatusLabel(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/stats/{service}/{user}/count.svg", InstrumentHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/stats/github/vivek-26/count.svg", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Equal(t, float64(1), testutil.ToFloat64(RequestsTotal.WithLabelValues("github", "404")))
+}
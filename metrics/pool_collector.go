@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var poolConnsDesc = prometheus.NewDesc(
+	"pvc_db_pool_conns",
+	"Current pgxpool connection counts by state.",
+	[]string{"state"}, nil,
+)
+
+// PoolCollector exposes a pgxpool.Pool's live connection stats as
+// Prometheus gauges, sampled fresh on every scrape.
+type PoolCollector struct {
+	pool *pgxpool.Pool
+}
+
+// NewPoolCollector wraps pool as a prometheus.Collector.
+func NewPoolCollector(pool *pgxpool.Pool) *PoolCollector {
+	return &PoolCollector{pool: pool}
+}
+
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolConnsDesc
+}
+
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(poolConnsDesc, prometheus.GaugeValue, float64(stat.AcquiredConns()), "acquired")
+	ch <- prometheus.MustNewConstMetric(poolConnsDesc, prometheus.GaugeValue, float64(stat.IdleConns()), "idle")
+	ch <- prometheus.MustNewConstMetric(poolConnsDesc, prometheus.GaugeValue, float64(stat.TotalConns()), "total")
+}
@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// BotMatcher recognizes known link-preview crawlers and CI cache-warmers
+// by their User-Agent so they can be served a badge without bumping the
+// view count.
+type BotMatcher struct {
+	patterns []*regexp.Regexp
+}
+
+// NewBotMatcher compiles patterns, which are matched against the
+// request's User-Agent header.
+func NewBotMatcher(patterns []string) (*BotMatcher, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid bot user-agent pattern %q", p)
+		}
+		compiled = append(compiled, re)
+	}
+	return &BotMatcher{patterns: compiled}, nil
+}
+
+// IsBot reports whether userAgent matches any configured pattern.
+func (b *BotMatcher) IsBot(userAgent string) bool {
+	for _, re := range b.patterns {
+		if re.MatchString(userAgent) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+)
+
+type contextKey int
+
+const skipIncrementKey contextKey = iota
+
+// withSkipIncrement marks r as a duplicate or bot hit: the handler should
+// still serve the badge, but must not bump the view count.
+func withSkipIncrement(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), skipIncrementKey, true))
+}
+
+// ShouldSkipIncrement reports whether the Middleware decided this
+// request's view count increment should be suppressed.
+func ShouldSkipIncrement(r *http.Request) bool {
+	skip, _ := r.Context().Value(skipIncrementKey).(bool)
+	return skip
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ipLimiterTTL bounds how long an idle IP's limiter is kept around.
+const ipLimiterTTL = time.Hour
+
+// ipLimiter hands out a token-bucket rate.Limiter per source IP, so a
+// single abusive client can't starve everyone else's burst allowance.
+// Idle entries are swept periodically so long-running processes don't
+// accumulate one limiter per visitor forever.
+type ipLimiter struct {
+	r     rate.Limit
+	burst int
+
+	mu       sync.Mutex
+	limiters map[string]*limiterEntry
+}
+
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newIPLimiter(requestsPerSecond float64, burst int) *ipLimiter {
+	l := &ipLimiter{
+		r:        rate.Limit(requestsPerSecond),
+		burst:    burst,
+		limiters: make(map[string]*limiterEntry),
+	}
+	go l.sweepLoop()
+	return l
+}
+
+func (l *ipLimiter) allow(ip string) bool {
+	l.mu.Lock()
+	entry, ok := l.limiters[ip]
+	if !ok {
+		entry = &limiterEntry{limiter: rate.NewLimiter(l.r, l.burst)}
+		l.limiters[ip] = entry
+	}
+	entry.lastSeen = time.Now()
+	limiter := entry.limiter
+	l.mu.Unlock()
+
+	return limiter.Allow()
+}
+
+func (l *ipLimiter) sweepLoop() {
+	ticker := time.NewTicker(ipLimiterTTL)
+	defer ticker.Stop()
+	for range ticker.C {
+		l.sweep()
+	}
+}
+
+func (l *ipLimiter) sweep() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for ip, entry := range l.limiters {
+		if now.Sub(entry.lastSeen) >= ipLimiterTTL {
+			delete(l.limiters, ip)
+		}
+	}
+}
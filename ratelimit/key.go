@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// clientIP returns the request's remote address without its port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// maskIP collapses an IPv4 address to its /24 and an IPv6 address to its
+// /64, so badge reloads from the same network dedupe together.
+func maskIP(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return (&net.IPNet{IP: v4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	return (&net.IPNet{IP: parsed.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}).String()
+}
+
+// dedupeKey hashes the identity a single view increment is attributed
+// to: the requester's /24 (or /64), User-Agent, service and user.
+func dedupeKey(ip, userAgent, service, user string) string {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%s", maskIP(ip), userAgent, service, user)))
+	return hex.EncodeToString(h[:])
+}
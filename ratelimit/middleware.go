@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// Middleware dedupes counter increments and shields the DB from abusive
+// or bot traffic before a request reaches the stats handler.
+type Middleware struct {
+	store     Store
+	bots      *BotMatcher
+	ipLimiter *ipLimiter
+}
+
+// NewMiddleware builds a Middleware. requestsPerSecond/burst bound how
+// many requests a single source IP may make.
+func NewMiddleware(store Store, bots *BotMatcher, requestsPerSecond float64, burst int) *Middleware {
+	return &Middleware{
+		store:     store,
+		bots:      bots,
+		ipLimiter: newIPLimiter(requestsPerSecond, burst),
+	}
+}
+
+// Wrap applies rate limiting, bot filtering and increment deduplication
+// to next. Requests that are bot or duplicate traffic still reach next,
+// just marked via ShouldSkipIncrement so the badge is still served.
+func (m *Middleware) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ip := clientIP(r)
+
+		if !m.ipLimiter.allow(ip) {
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		if m.bots.IsBot(r.UserAgent()) {
+			next(w, withSkipIncrement(r))
+			return
+		}
+
+		vars := mux.Vars(r)
+		key := dedupeKey(ip, r.UserAgent(), vars["service"], vars["user"])
+		seen, err := m.store.SeenRecently(r.Context(), key)
+		if err != nil || seen {
+			next(w, withSkipIncrement(r))
+			return
+		}
+
+		next(w, r)
+	}
+}
@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBotMatcher_IsBot(t *testing.T) {
+	matcher, err := NewBotMatcher([]string{"Slackbot", "Twitterbot"})
+	require.NoError(t, err)
+
+	require.True(t, matcher.IsBot("Slackbot-LinkExpanding 1.0"))
+	require.False(t, matcher.IsBot("Mozilla/5.0"))
+}
+
+func TestMemoryStore_SeenRecently(t *testing.T) {
+	store := NewMemoryStore(time.Hour)
+
+	seen, err := store.SeenRecently(nil, "key")
+	require.NoError(t, err)
+	require.False(t, seen)
+
+	seen, err = store.SeenRecently(nil, "key")
+	require.NoError(t, err)
+	require.True(t, seen)
+}
+
+func TestMaskIP(t *testing.T) {
+	require.Equal(t, "203.0.113.0/24", maskIP("203.0.113.42"))
+	require.Equal(t, "not-an-ip", maskIP("not-an-ip"))
+}
+
+func TestDedupeKey_StableForSameIdentity(t *testing.T) {
+	a := dedupeKey("203.0.113.1", "curl/7", "github", "vivek-26")
+	b := dedupeKey("203.0.113.99", "curl/7", "github", "vivek-26")
+	require.Equal(t, a, b, "same /24 should dedupe together")
+
+	c := dedupeKey("198.51.100.1", "curl/7", "github", "vivek-26")
+	require.NotEqual(t, a, c)
+}
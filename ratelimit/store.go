@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store deduplicates increments: SeenRecently marks key as seen and
+// reports whether it was already seen within the store's TTL window.
+// The in-memory implementation below is the default; a Redis-backed one
+// can satisfy the same interface for multi-instance deployments.
+type Store interface {
+	SeenRecently(ctx context.Context, key string) (bool, error)
+}
+
+// memoryStore is a process-local Store backed by a map, swept
+// periodically to bound its size.
+type memoryStore struct {
+	ttl time.Duration
+
+	mu     sync.Mutex
+	seenAt map[string]time.Time
+}
+
+// NewMemoryStore builds a Store that remembers a key for ttl.
+func NewMemoryStore(ttl time.Duration) Store {
+	s := &memoryStore{ttl: ttl, seenAt: make(map[string]time.Time)}
+	go s.sweepLoop()
+	return s
+}
+
+func (s *memoryStore) SeenRecently(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if last, ok := s.seenAt[key]; ok && now.Sub(last) < s.ttl {
+		return true, nil
+	}
+	s.seenAt[key] = now
+	return false, nil
+}
+
+func (s *memoryStore) sweepLoop() {
+	ticker := time.NewTicker(s.ttl)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.sweep()
+	}
+}
+
+func (s *memoryStore) sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, seenAt := range s.seenAt {
+		if now.Sub(seenAt) >= s.ttl {
+			delete(s.seenAt, key)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -22,7 +25,18 @@ func main() {
 		logger.Panic(err.Error())
 	}
 
-	appInst, err := app.New(config, logger)
+	logLevel := zap.NewAtomicLevel()
+	if err := logLevel.UnmarshalText([]byte(config.LogLevel)); err != nil {
+		logLevel.SetLevel(zap.InfoLevel)
+	}
+	zapConfig := zap.NewProductionConfig()
+	zapConfig.Level = logLevel
+	logger, err = zapConfig.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	appInst, err := app.New(config, logger, logLevel)
 	if err != nil {
 		logger.Panic(err.Error())
 		return
@@ -39,9 +53,25 @@ func main() {
 	case killSig := <-killSigs:
 		logger.Info("received kill signal", zap.String("signal", killSig.String()))
 	case serveErr := <-appErr:
-		logger.Error("server crashed", zap.String("error", serveErr.Error()))
+		if !errors.Is(serveErr, http.ErrServerClosed) {
+			logger.Error("server crashed", zap.String("error", serveErr.Error()))
+		}
 	}
 
-	appInst.ShutDown()
-	logger.Info("application shutdown complete")
+	ctx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+
+	shutdownDone := make(chan struct{})
+	go func() {
+		appInst.ShutDown(ctx)
+		close(shutdownDone)
+	}()
+
+	select {
+	case <-shutdownDone:
+		logger.Info("application shutdown complete")
+	case killSig := <-killSigs:
+		logger.Warn("received second kill signal, forcing immediate exit", zap.String("signal", killSig.String()))
+		os.Exit(1)
+	}
 }